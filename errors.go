@@ -0,0 +1,8 @@
+package lrucache
+
+import "errors"
+
+// ErrCacheFull is returned by PutE and PutIfAbsent when inserting a new
+// key would require evicting an entry, but every entry currently in the
+// cache is pinned.
+var ErrCacheFull = errors.New("lrucache: cache full, all entries pinned")
@@ -0,0 +1,73 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewWithEvictInvokesCallbackOnCapacityEviction(t *testing.T) {
+	var gotKey, gotValue interface{}
+	var gotReason EvictReason
+	c := NewWithEvict(1, func(key, value interface{}, reason EvictReason) {
+		gotKey, gotValue, gotReason = key, value, reason
+	})
+	c.Put("a", 1)
+	c.Put("b", 2)
+	if gotKey != "a" || gotValue != 1 || gotReason != EvictCapacity {
+		t.Fatalf("OnEvict called with (%v, %v, %v), want (a, 1, capacity)", gotKey, gotValue, gotReason)
+	}
+}
+
+func TestNewWithCallbacksInvokesOnExpireOnTTLEviction(t *testing.T) {
+	// OnEvict/OnExpire fire from the sweeper goroutine, so results must be
+	// handed back to the test goroutine through channels rather than plain
+	// variables read after a timing-based sleep.
+	type evicted struct {
+		key, value interface{}
+		reason     EvictReason
+	}
+	evictCh := make(chan evicted, 1)
+	expireCh := make(chan interface{}, 1)
+	c := NewWithCallbacks(10, 1, Callbacks{
+		OnEvict: func(key, value interface{}, reason EvictReason) {
+			evictCh <- evicted{key: key, value: value, reason: reason}
+		},
+		OnExpire: func(key, value interface{}) {
+			expireCh <- key
+		},
+	})
+	defer c.Stop()
+	c.Put("a", 1)
+
+	select {
+	case got := <-evictCh:
+		if got.key != "a" || got.value != 1 || got.reason != EvictTTL {
+			t.Fatalf("OnEvict called with (%v, %v, %v), want (a, 1, ttl)", got.key, got.value, got.reason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for OnEvict")
+	}
+
+	select {
+	case key := <-expireCh:
+		if key != "a" {
+			t.Fatalf("OnExpire called with key %v, want a", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for OnExpire")
+	}
+}
+
+func TestNewWithCallbacksOnEvictInvokedOnManualRemove(t *testing.T) {
+	var gotReason EvictReason
+	c := NewWithCallbacks(10, 0, Callbacks{
+		OnEvict: func(key, value interface{}, reason EvictReason) {
+			gotReason = reason
+		},
+	})
+	c.Put("a", 1)
+	c.Remove("a")
+	if gotReason != EvictManual {
+		t.Fatalf("OnEvict reason = %v, want manual", gotReason)
+	}
+}
@@ -0,0 +1,40 @@
+package lrucache
+
+// expItem is an entry in a cache's expiration min-heap, ordered by
+// expireTime. Items are never removed from the heap when an entry's TTL is
+// refreshed or the entry is removed from the cache; instead the sweeper
+// discards them lazily when popped, by checking whether they still match
+// the current state of the entry they reference.
+type expItem struct {
+	e          *entry
+	expireTime int64
+	index      int
+}
+
+type expHeap []*expItem
+
+func (h expHeap) Len() int { return len(h) }
+
+func (h expHeap) Less(i, j int) bool { return h[i].expireTime < h[j].expireTime }
+
+func (h expHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expHeap) Push(x interface{}) {
+	item := x.(*expItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *expHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,341 @@
+// Package generic provides a generic, type-safe counterpart to the
+// interface{}-based lrucache.LRUCache API, following the pattern used by
+// go-ethereum's common/lru and hashicorp/golang-lru/v2. It lives in its
+// own package because Go does not allow a generic function to share a
+// name with the package-level New/NewWithTTL already exported by
+// lrucache.
+package generic
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache is a generic, type-safe LRU cache for comparable key types K and
+// arbitrary value types V. It provides the same recently-used semantics as
+// lrucache.LRUCache but stores K and V directly in its entries instead of
+// boxing them as interface{}, avoiding the associated allocations and type
+// assertions.
+type Cache[K comparable, V any] struct {
+	mu         sync.Mutex
+	capacity   int
+	ttlSeconds int64
+	stopped    bool
+	head       *centry[K, V]
+	tail       *centry[K, V]
+	hash       map[K]*centry[K, V]
+	expHeap    cexpHeap[K, V]
+	timer      *time.Timer
+	stopCh     chan struct{}
+	sweeping   bool
+}
+
+type centry[K comparable, V any] struct {
+	next, prev *centry[K, V]
+	key        K
+	value      V
+	expireTime int64 // unix time seconds
+}
+
+// New returns a new Cache instance with the specified capacity and TTL
+// support disabled. Panics if capacity <= 0.
+func New[K comparable, V any](capacity int) *Cache[K, V] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("invalid capacity: %d\n", capacity))
+	}
+	return &Cache[K, V]{
+		capacity: capacity,
+		hash:     make(map[K]*centry[K, V]),
+	}
+}
+
+// NewWithTTL returns a new Cache instance with the specified capacity and
+// ttl. Panics if capacity <= 0 or ttl < 0. A ttl value of 0 disables TTL
+// support.
+func NewWithTTL[K comparable, V any](capacity int, ttl time.Duration) *Cache[K, V] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("invalid capacity: %d\n", capacity))
+	}
+	if ttl < 0 {
+		panic(fmt.Sprintf("invalid ttl: %s\n", ttl))
+	}
+	c := &Cache[K, V]{
+		capacity:   capacity,
+		ttlSeconds: ttlSeconds(ttl),
+		hash:       make(map[K]*centry[K, V]),
+	}
+	if c.ttlSeconds > 0 {
+		c.startSweeper()
+	}
+	return c
+}
+
+// ttlSeconds converts ttl to a whole number of seconds, rounding up so
+// that any positive ttl below one second still yields at least 1 rather
+// than truncating to 0, which would be indistinguishable from the ttl==0
+// "TTL disabled" sentinel used throughout the cache.
+func ttlSeconds(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return int64((ttl + time.Second - 1) / time.Second)
+}
+
+// startSweeper lazily starts the goroutine that evicts expired entries. It
+// is idempotent and must be called under lock.
+func (c *Cache[K, V]) startSweeper() {
+	if c.sweeping {
+		return
+	}
+	c.sweeping = true
+	c.stopCh = make(chan struct{})
+	c.timer = time.NewTimer(time.Hour)
+	c.timer.Stop()
+	go c.sweep()
+}
+
+// sweep evicts expired entries as their timers fire. It pops entries from
+// expHeap, which is kept ordered by expireTime, until the heap is empty or
+// its minimum has not yet expired, then rearms the timer for the next
+// expiration. Stale heap items, left behind by TTL refreshes, removals, or
+// an entry being reused by Put for a different key, are discarded without
+// eviction.
+func (c *Cache[K, V]) sweep() {
+	for {
+		select {
+		case <-c.timer.C:
+			c.mu.Lock()
+			now := time.Now().Unix()
+			for c.expHeap.Len() > 0 {
+				item := c.expHeap[0]
+				e, ok := c.hash[item.e.key]
+				if !ok || e != item.e || e.expireTime != item.expireTime {
+					heap.Pop(&c.expHeap)
+					continue
+				}
+				if item.expireTime > now {
+					break
+				}
+				heap.Pop(&c.expHeap)
+				c.unlink(e)
+				delete(c.hash, e.key)
+			}
+			c.armTimer(now)
+			c.mu.Unlock()
+		case <-c.stopCh:
+			c.timer.Stop()
+			return
+		}
+	}
+}
+
+// pushExpiry pushes a heap entry tracking e's current expireTime and
+// rearms the timer if e is now the soonest expiration. It starts the
+// sweeper if it is not already running. Must be called under lock, and
+// only when e.expireTime > 0.
+func (c *Cache[K, V]) pushExpiry(e *centry[K, V]) {
+	if !c.sweeping {
+		c.startSweeper()
+	}
+	heap.Push(&c.expHeap, &cexpItem[K, V]{e: e, expireTime: e.expireTime})
+	c.armTimer(time.Now().Unix())
+}
+
+// armTimer resets the sweeper's timer to fire at the soonest expiration
+// remaining in expHeap, or leaves it disarmed if the heap is empty. Must be
+// called under lock.
+func (c *Cache[K, V]) armTimer(now int64) {
+	if !c.timer.Stop() {
+		select {
+		case <-c.timer.C:
+		default:
+		}
+	}
+	if c.expHeap.Len() == 0 {
+		return
+	}
+	d := time.Duration(c.expHeap[0].expireTime-now) * time.Second
+	if d < 0 {
+		d = 0
+	}
+	c.timer.Reset(d)
+}
+
+// Get returns the value associated with the specified key and promotes the
+// entry to the head of the recently used list. The second return value is
+// false if the key is not present in the cache.
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.hash[key]
+	if !ok {
+		return value, false
+	}
+	if e != c.head {
+		c.unlink(e)
+		c.prepend(e)
+	}
+	return e.value, true
+}
+
+// Peek returns the value associated with the specified key without
+// promoting the entry to the head of the recently used list.
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.hash[key]
+	if !ok {
+		return value, false
+	}
+	return e.value, true
+}
+
+// Contains reports whether key is present in the cache, without promoting
+// the entry to the head of the recently used list.
+func (c *Cache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.hash[key]
+	return ok
+}
+
+// Put sets the value for the specified key and promotes the entry to the
+// head of the recently used list. It returns true if putting key caused an
+// existing entry to be evicted because the cache was at capacity. If the
+// cache is full and key is not already present, the entry evicted from the
+// tail of the recently used list is reused to hold key and value, so Put
+// allocates no new entry in that case. When TTL is enabled, Put still
+// allocates the heap item that tracks the entry's expiration.
+func (c *Cache[K, V]) Put(key K, value V) (evicted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var expireTime int64
+	if c.ttlSeconds > 0 {
+		expireTime = time.Now().Unix() + c.ttlSeconds
+	}
+	if e, ok := c.hash[key]; ok {
+		e.value = value
+		e.expireTime = expireTime
+		if expireTime > 0 {
+			c.pushExpiry(e)
+		}
+		if e != c.head {
+			c.unlink(e)
+			c.prepend(e)
+		}
+		return false
+	}
+	if len(c.hash) >= c.capacity {
+		// reuse the evicted tail entry rather than allocating a new one
+		e := c.tail
+		c.unlink(e)
+		delete(c.hash, e.key)
+		e.key = key
+		e.value = value
+		e.expireTime = expireTime
+		c.prepend(e)
+		c.hash[key] = e
+		if expireTime > 0 {
+			c.pushExpiry(e)
+		}
+		return true
+	}
+	e := &centry[K, V]{key: key, value: value, expireTime: expireTime}
+	c.prepend(e)
+	c.hash[key] = e
+	if expireTime > 0 {
+		c.pushExpiry(e)
+	}
+	return false
+}
+
+// Remove removes the entry for the specified key and returns its value.
+// The second return value is false if the key was not present in the cache.
+func (c *Cache[K, V]) Remove(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.hash[key]
+	if !ok {
+		return value, false
+	}
+	c.unlink(e)
+	delete(c.hash, key)
+	return e.value, true
+}
+
+// Keys returns the keys currently in the cache, ordered from most to least
+// recently used.
+func (c *Cache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]K, 0, len(c.hash))
+	for e := c.head; e != nil; e = e.next {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// Len returns the number of entries currently in the cache.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.hash)
+}
+
+// Purge removes all entries from the cache.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head = nil
+	c.tail = nil
+	c.hash = make(map[K]*centry[K, V])
+	c.expHeap = c.expHeap[:0]
+	if c.sweeping {
+		c.armTimer(time.Now().Unix())
+	}
+}
+
+// Stop terminates the goroutine used to purge expired entries when TTL is
+// enabled. It has no effect if TTL is not enabled. It should be called if
+// the cache is no longer in use prior to program termination.
+func (c *Cache[K, V]) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+	c.stopped = true
+	if c.sweeping {
+		close(c.stopCh)
+	}
+}
+
+// must be called under lock
+func (c *Cache[K, V]) unlink(e *centry[K, V]) {
+	if e.prev == nil {
+		c.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		c.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	e.prev = nil
+	e.next = nil
+}
+
+// must be called under lock
+func (c *Cache[K, V]) prepend(e *centry[K, V]) {
+	e.next = c.head
+	if c.head != nil {
+		c.head.prev = e
+	}
+	c.head = e
+	if c.tail == nil {
+		c.tail = e
+	}
+}
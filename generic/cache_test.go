@@ -0,0 +1,74 @@
+package generic
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheEvictsAtCapacity(t *testing.T) {
+	c := New[string, int](2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	if evicted := c.Put("c", 3); !evicted {
+		t.Fatalf("expected eviction when over capacity")
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a evicted")
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Fatalf("Get(b) = (%v, %v), want (2, true)", v, ok)
+	}
+}
+
+func TestCacheExpiresWithTTL(t *testing.T) {
+	// The cache tracks expiration with unix-second granularity, so ttl is
+	// rounded up to whole seconds; use a ttl and sleep that respect that.
+	c := NewWithTTL[string, int](10, time.Second)
+	defer c.Stop()
+	c.Put("a", 1)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected a present immediately after Put")
+	}
+	time.Sleep(1300 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a expired")
+	}
+}
+
+func TestCacheSubSecondTTLStillExpires(t *testing.T) {
+	// A ttl below one second must round up rather than truncate to 0,
+	// which would be indistinguishable from TTL being disabled.
+	c := NewWithTTL[string, int](10, 50*time.Millisecond)
+	defer c.Stop()
+	c.Put("a", 1)
+	time.Sleep(1300 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a expired")
+	}
+}
+
+func TestCacheReuseOfEvictedEntryPreservesTTL(t *testing.T) {
+	c := NewWithTTL[string, int](1, time.Hour)
+	defer c.Stop()
+	c.Put("a", 1)
+	c.Put("b", 2) // reuses a's evicted entry node
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a evicted")
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Fatalf("Get(b) = (%v, %v), want (2, true)", v, ok)
+	}
+	// the reused node must carry b's own expiration, tracked by the heap
+	c.mu.Lock()
+	e := c.hash["b"]
+	tracked := false
+	for _, item := range c.expHeap {
+		if item.e == e && item.expireTime == e.expireTime {
+			tracked = true
+		}
+	}
+	c.mu.Unlock()
+	if e.expireTime == 0 || !tracked {
+		t.Fatalf("expected b's reused entry to carry a TTL tracked by the expiration heap")
+	}
+}
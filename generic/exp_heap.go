@@ -0,0 +1,41 @@
+package generic
+
+// cexpItem is an entry in a Cache's expiration min-heap, ordered by
+// expireTime. Items are never removed from the heap when an entry's TTL
+// is refreshed or the entry is removed (or reused for a different key, as
+// Put does when reusing an evicted tail node); the sweeper discards them
+// lazily when popped, by checking whether the entry they reference still
+// carries the expireTime recorded here.
+type cexpItem[K comparable, V any] struct {
+	e          *centry[K, V]
+	expireTime int64
+	index      int
+}
+
+type cexpHeap[K comparable, V any] []*cexpItem[K, V]
+
+func (h cexpHeap[K, V]) Len() int { return len(h) }
+
+func (h cexpHeap[K, V]) Less(i, j int) bool { return h[i].expireTime < h[j].expireTime }
+
+func (h cexpHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *cexpHeap[K, V]) Push(x interface{}) {
+	item := x.(*cexpItem[K, V])
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *cexpHeap[K, V]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
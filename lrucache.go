@@ -1,6 +1,7 @@
 package lrucache
 
 import (
+	"container/heap"
 	"fmt"
 	"sync"
 	"time"
@@ -15,7 +16,9 @@ type LRUCache interface {
 	// entry to the head of the recently used list.
 	// If Put causes the cache to exceed its capacity, the least recently
 	// accessed entry is removed, i.e., the entry at the tail of the recently
-	// used list.
+	// used list. If every entry is pinned, the cache is already at capacity,
+	// and key is not already present, the insert is silently dropped instead
+	// of returning an error; use PutE if that case needs to be detected.
 	Put(key, value interface{})
 	// Remove removes the entry for the specified key, and returns
 	// the associated value, or nil if the key is not present in the cache.
@@ -29,28 +32,119 @@ type LRUCache interface {
 	TTLSeconds() int64
 	// Clear removes all entries from the cache.
 	Clear()
+	// PutWithTTL sets the value for the specified key with a TTL specific
+	// to that entry, independent of the cache's configured TTL, and
+	// promotes the entry to the head of the recently used list. A ttl of
+	// 0 means the entry never expires due to TTL, though it remains
+	// subject to capacity eviction.
+	PutWithTTL(key, value interface{}, ttl time.Duration)
+	// PutE behaves like Put, but returns ErrCacheFull instead of evicting
+	// an entry if the cache is at capacity and every existing entry is
+	// pinned.
+	PutE(key, value interface{}) error
+	// PutIfAbsent sets the value for key only if it is not already
+	// present, and returns ErrCacheFull if the cache is at capacity and
+	// every existing entry is pinned.
+	PutIfAbsent(key, value interface{}) error
+	// Pin marks the entry for key as pinned, exempting it from capacity
+	// and TTL eviction until it is unpinned. Pins nest: an entry pinned N
+	// times requires N calls to Unpin before it is eligible for eviction
+	// again. It has no effect if key is not present in the cache.
+	Pin(key interface{})
+	// Unpin reverses one prior call to Pin. It has no effect if key is
+	// not present in the cache, or is not currently pinned.
+	Unpin(key interface{})
 	// Stop terminates the goroutine used to purge expired entries when TTL
 	// is enabled. It has no effect if TTL is not enabled. It should be called
 	// if the cache is no longer in use prior to program termination.
 	Stop()
 }
 
+// EvictReason indicates why an entry was removed from the cache when
+// passed to an EvictCallback.
+type EvictReason int
+
+const (
+	// EvictCapacity indicates an entry was evicted because the cache had
+	// exceeded its capacity.
+	EvictCapacity EvictReason = iota
+	// EvictTTL indicates an entry was evicted because its TTL expired.
+	EvictTTL
+	// EvictManual indicates an entry was removed by an explicit call to
+	// Remove.
+	EvictManual
+	// EvictClear indicates an entry was removed as part of a call to
+	// Clear.
+	EvictClear
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictTTL:
+		return "ttl"
+	case EvictManual:
+		return "manual"
+	case EvictClear:
+		return "clear"
+	default:
+		return "unknown"
+	}
+}
+
+// EvictCallback is invoked whenever an entry is removed from the cache,
+// whether by capacity eviction, TTL expiration, an explicit Remove, or
+// Clear. It is always invoked outside of the cache's internal lock, so it
+// may safely call back into the cache that invoked it.
+type EvictCallback func(key, value interface{}, reason EvictReason)
+
+// ExpireCallback is invoked whenever an entry is evicted specifically
+// because its TTL expired. Like EvictCallback, it is invoked outside of the
+// cache's internal lock.
+type ExpireCallback func(key, value interface{})
+
+// Callbacks holds the optional callbacks that may be supplied to
+// NewWithCallbacks.
+type Callbacks struct {
+	// OnEvict, if non-nil, is invoked for every entry removed from the
+	// cache, regardless of reason.
+	OnEvict EvictCallback
+	// OnExpire, if non-nil, is invoked in addition to OnEvict whenever an
+	// entry is evicted because its TTL expired.
+	OnExpire ExpireCallback
+}
+
 // New returns a new LRUCache instance with the specified capacity
 // and TTL support disabled. Panics if capacity <= 0.
 func New(capacity int) LRUCache {
-	if capacity <= 0 {
-		panic(fmt.Sprintf("invalid capacity: %d\n", capacity))
-	}
-	return &lrucache{
-		capacity: capacity,
-		hash:     make(map[interface{}]*entry),
-	}
+	return newLRUCache(capacity, 0, Callbacks{})
 }
 
 // NewWithTTL returns a new LRUCache instance with the specified capacity
 // and ttlSeconds. Panics if capacity <= 0 or ttlSeconds < 0. A ttlSeconds
 // value of 0 disables TTL support.
 func NewWithTTL(capacity int, ttlSeconds int64) LRUCache {
+	return newLRUCache(capacity, ttlSeconds, Callbacks{})
+}
+
+// NewWithEvict returns a new LRUCache instance with the specified capacity
+// and TTL support disabled, which invokes onEvict whenever an entry is
+// removed from the cache. Panics if capacity <= 0.
+func NewWithEvict(capacity int, onEvict EvictCallback) LRUCache {
+	return newLRUCache(capacity, 0, Callbacks{OnEvict: onEvict})
+}
+
+// NewWithCallbacks returns a new LRUCache instance with the specified
+// capacity and ttlSeconds, which invokes the supplied callbacks as entries
+// are evicted or expire. Panics if capacity <= 0 or ttlSeconds < 0. A
+// ttlSeconds value of 0 disables the cache-wide TTL; entries stored with
+// PutWithTTL still expire after their own ttl.
+func NewWithCallbacks(capacity int, ttlSeconds int64, cb Callbacks) LRUCache {
+	return newLRUCache(capacity, ttlSeconds, cb)
+}
+
+func newLRUCache(capacity int, ttlSeconds int64, cb Callbacks) *lrucache {
 	if capacity <= 0 {
 		panic(fmt.Sprintf("invalid capacity: %d\n", capacity))
 	}
@@ -61,48 +155,117 @@ func NewWithTTL(capacity int, ttlSeconds int64) LRUCache {
 		capacity:   capacity,
 		ttlSeconds: ttlSeconds,
 		hash:       make(map[interface{}]*entry),
+		onEvict:    cb.OnEvict,
+		onExpire:   cb.OnExpire,
+	}
+	if ttlSeconds > 0 {
+		c.startSweeper()
 	}
-	go pruneExpiredEntries(c)
 	return c
 }
 
-func pruneExpiredEntries(c *lrucache) {
-	if c.ttlSeconds == 0 || c.stopped {
+// startSweeper lazily starts the goroutine that evicts expired entries. It
+// is idempotent, does nothing once the cache has been Stop()'d, and must be
+// called under lock.
+func (c *lrucache) startSweeper() {
+	if c.sweeping || c.stopped {
 		return
 	}
-	var stopped bool
+	c.sweeping = true
+	c.stopCh = make(chan struct{})
+	c.timer = time.NewTimer(time.Hour)
+	c.timer.Stop()
+	go c.sweep()
+}
+
+// sweep evicts expired entries as their timers fire. It pops entries from
+// expHeap, which is kept ordered by expireTime, until the heap is empty or
+// its minimum has not yet expired, then rearms the timer for the next
+// expiration. Stale heap items, left behind by TTL refreshes or removals,
+// are discarded without eviction.
+func (c *lrucache) sweep() {
 	for {
-		if stopped {
-			return
-		}
-		time.Sleep(200 * time.Millisecond)
-		func() {
-			now := time.Now().Unix()
+		select {
+		case <-c.timer.C:
+			var expired []*entry
 			c.Lock()
-			defer c.Unlock()
-			if c.stopped {
-				stopped = true
-				return
-			}
-			for e := c.head; e != nil; {
-				// save next pointer, as unlink() will set to nil
-				next := e.next
-				if e.expireTime <= now {
-					c.unlink(e)
-					delete(c.hash, e.key)
+			now := time.Now().Unix()
+			for c.expHeap.Len() > 0 {
+				item := c.expHeap[0]
+				e, ok := c.hash[item.e.key]
+				if !ok || e != item.e || e.expireTime != item.expireTime {
+					heap.Pop(&c.expHeap)
+					continue
+				}
+				if item.expireTime > now {
+					break
+				}
+				heap.Pop(&c.expHeap)
+				if e.pinCount > 0 {
+					// pinned entries are exempt from TTL eviction; Unpin
+					// re-pushes a fresh expiry item once the entry is
+					// fully unpinned, so dropping this one is safe
+					continue
 				}
-				e = next
+				c.unlink(e)
+				delete(c.hash, e.key)
+				expired = append(expired, e)
 			}
-		}()
+			c.armTimer(now)
+			c.Unlock()
+			for _, e := range expired {
+				c.fireEvict(e, EvictTTL)
+			}
+		case <-c.stopCh:
+			c.timer.Stop()
+			return
+		}
 	}
 }
 
+// pushExpiry pushes a heap entry tracking e's current expireTime and
+// rearms the timer if e is now the soonest expiration. It starts the
+// sweeper if it is not already running, and does nothing if the cache has
+// been Stop()'d. Must be called under lock, and only when e.expireTime > 0.
+func (c *lrucache) pushExpiry(e *entry) {
+	if !c.sweeping {
+		c.startSweeper()
+		if !c.sweeping {
+			// Stop()'d before ever needing a sweeper; no timer to arm.
+			return
+		}
+	}
+	heap.Push(&c.expHeap, &expItem{e: e, expireTime: e.expireTime})
+	c.armTimer(time.Now().Unix())
+}
+
+// armTimer resets the sweeper's timer to fire at the soonest expiration
+// remaining in expHeap, or leaves it disarmed if the heap is empty. Must be
+// called under lock.
+func (c *lrucache) armTimer(now int64) {
+	if !c.timer.Stop() {
+		select {
+		case <-c.timer.C:
+		default:
+		}
+	}
+	if c.expHeap.Len() == 0 {
+		return
+	}
+	d := time.Duration(c.expHeap[0].expireTime-now) * time.Second
+	if d < 0 {
+		d = 0
+	}
+	c.timer.Reset(d)
+}
+
 type entry struct {
 	next       *entry
 	prev       *entry
 	key        interface{}
 	value      interface{}
 	expireTime int64 // unix time seconds
+	pinCount   int
 }
 
 type lrucache struct {
@@ -113,6 +276,12 @@ type lrucache struct {
 	head       *entry
 	tail       *entry
 	hash       map[interface{}]*entry
+	onEvict    EvictCallback
+	onExpire   ExpireCallback
+	expHeap    expHeap
+	timer      *time.Timer
+	stopCh     chan struct{}
+	sweeping   bool
 }
 
 func (c *lrucache) Get(key interface{}) (value interface{}) {
@@ -131,45 +300,140 @@ func (c *lrucache) Get(key interface{}) (value interface{}) {
 }
 
 func (c *lrucache) Put(key, value interface{}) {
+	_ = c.PutE(key, value)
+}
+
+func (c *lrucache) PutE(key, value interface{}) error {
+	var expireTime int64
+	if c.ttlSeconds > 0 {
+		expireTime = time.Now().Unix() + c.ttlSeconds
+	}
+	c.Lock()
+	evicted, err := c.putLocked(key, value, expireTime)
+	c.Unlock()
+	if evicted != nil {
+		c.fireEvict(evicted, EvictCapacity)
+	}
+	return err
+}
+
+func (c *lrucache) PutWithTTL(key, value interface{}, ttl time.Duration) {
+	var expireTime int64
+	if ttl > 0 {
+		expireTime = time.Now().Unix() + int64(ttl/time.Second)
+	}
+	c.Lock()
+	evicted, _ := c.putLocked(key, value, expireTime)
+	c.Unlock()
+	if evicted != nil {
+		c.fireEvict(evicted, EvictCapacity)
+	}
+}
+
+func (c *lrucache) PutIfAbsent(key, value interface{}) error {
+	var expireTime int64
+	if c.ttlSeconds > 0 {
+		expireTime = time.Now().Unix() + c.ttlSeconds
+	}
+	c.Lock()
+	var evicted *entry
+	var err error
+	if _, ok := c.hash[key]; !ok {
+		evicted, err = c.putLocked(key, value, expireTime)
+	}
+	c.Unlock()
+	if evicted != nil {
+		c.fireEvict(evicted, EvictCapacity)
+	}
+	return err
+}
+
+func (c *lrucache) Pin(key interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	if e := c.hash[key]; e != nil {
+		e.pinCount++
+	}
+}
+
+func (c *lrucache) Unpin(key interface{}) {
 	c.Lock()
 	defer c.Unlock()
+	e := c.hash[key]
+	if e == nil || e.pinCount == 0 {
+		return
+	}
+	e.pinCount--
+	if e.pinCount == 0 && e.expireTime > 0 {
+		// the sweeper drops a pinned entry's heap item without
+		// re-arming it, since expiration is now driven solely by the
+		// heap/timer rather than a periodic scan; re-push it here so an
+		// already-elapsed TTL is picked up on the next tick instead of
+		// leaking until the entry is next Put.
+		c.pushExpiry(e)
+	}
+}
+
+// putLocked inserts or updates key with value and expireTime (0 meaning no
+// expiration), and returns the evicted entry, if any, so the caller can
+// report it via fireEvict outside the lock. It returns ErrCacheFull,
+// without modifying the cache, if key is absent, the cache is at
+// capacity, and every existing entry is pinned. Must be called under
+// lock.
+func (c *lrucache) putLocked(key, value interface{}, expireTime int64) (evicted *entry, err error) {
 	e := c.hash[key]
 	if e != nil {
 		e.value = value
-		if c.ttlSeconds > 0 {
-			e.expireTime = time.Now().Unix() + c.ttlSeconds
+		e.expireTime = expireTime
+		if expireTime > 0 {
+			c.pushExpiry(e)
 		}
 		// move entry to head of list if not already there
 		if e != c.head {
 			c.unlink(e)
 			c.prepend(e)
 		}
-		return
+		return nil, nil
 	}
-	e = &entry{key: key, value: value}
-	if c.ttlSeconds > 0 {
-		e.expireTime = time.Now().Unix() + c.ttlSeconds
+	// if at capacity, evict the least recently used unpinned entry first
+	if len(c.hash) >= c.capacity {
+		victim := c.tail
+		for victim != nil && victim.pinCount > 0 {
+			victim = victim.prev
+		}
+		if victim == nil {
+			return nil, ErrCacheFull
+		}
+		c.unlink(victim)
+		delete(c.hash, victim.key)
+		evicted = victim
 	}
+	e = &entry{key: key, value: value, expireTime: expireTime}
 	// insert new entry at head of list and in hash
 	c.prepend(e)
 	c.hash[key] = e
-	// if over capacity, remove last (lru) entry
-	if len(c.hash) > c.capacity {
-		last := c.tail
-		c.unlink(last)
-		delete(c.hash, last.key)
+	if expireTime > 0 {
+		c.pushExpiry(e)
 	}
+	return evicted, nil
 }
 
 func (c *lrucache) Remove(key interface{}) (value interface{}) {
 	c.Lock()
-	defer c.Unlock()
+	var removed *entry
+	defer func() {
+		c.Unlock()
+		if removed != nil {
+			c.fireEvict(removed, EvictManual)
+		}
+	}()
 	e := c.hash[key]
 	if e == nil {
 		return nil
 	}
 	c.unlink(e)
 	delete(c.hash, key)
+	removed = e
 	return e.value
 }
 
@@ -189,16 +453,46 @@ func (c *lrucache) TTLSeconds() int64 {
 
 func (c *lrucache) Clear() {
 	c.Lock()
-	defer c.Unlock()
+	var cleared []*entry
+	defer func() {
+		c.Unlock()
+		for _, e := range cleared {
+			c.fireEvict(e, EvictClear)
+		}
+	}()
+	for e := c.head; e != nil; e = e.next {
+		cleared = append(cleared, e)
+	}
 	c.head = nil
 	c.tail = nil
 	c.hash = make(map[interface{}]*entry)
+	c.expHeap = c.expHeap[:0]
+	if c.sweeping {
+		c.armTimer(time.Now().Unix())
+	}
 }
 
 func (c *lrucache) Stop() {
 	c.Lock()
 	defer c.Unlock()
+	if c.stopped {
+		return
+	}
 	c.stopped = true
+	if c.sweeping {
+		close(c.stopCh)
+	}
+}
+
+// fireEvict invokes onEvict and, for EvictTTL, onExpire. It must be called
+// outside of the cache's lock.
+func (c *lrucache) fireEvict(e *entry, reason EvictReason) {
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value, reason)
+	}
+	if reason == EvictTTL && c.onExpire != nil {
+		c.onExpire(e.key, e.value)
+	}
 }
 
 // must be called under lock
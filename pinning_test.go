@@ -0,0 +1,38 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPinBlocksCapacityEviction(t *testing.T) {
+	c := New(1)
+	c.Put("a", 1)
+	c.Pin("a")
+	if err := c.PutE("b", 2); err != ErrCacheFull {
+		t.Fatalf("PutE returned %v, want ErrCacheFull", err)
+	}
+	c.Unpin("a")
+	if err := c.PutE("b", 2); err != nil {
+		t.Fatalf("PutE returned %v, want nil", err)
+	}
+	if c.Get("a") != nil {
+		t.Fatalf("expected a evicted after unpin")
+	}
+}
+
+func TestUnpinExpiresElapsedTTL(t *testing.T) {
+	c := NewWithTTL(10, 0)
+	defer c.Stop()
+	c.PutWithTTL("a", 1, 50*time.Millisecond)
+	c.Pin("a")
+	time.Sleep(250 * time.Millisecond)
+	if c.Get("a") == nil {
+		t.Fatalf("expected a still present while pinned past its TTL")
+	}
+	c.Unpin("a")
+	time.Sleep(250 * time.Millisecond)
+	if c.Get("a") != nil {
+		t.Fatalf("expected a expired shortly after unpin")
+	}
+}
@@ -0,0 +1,165 @@
+package lrucache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"time"
+)
+
+// Hasher computes a shard-routing hash for a cache key.
+type Hasher func(key interface{}) uint64
+
+// NewSharded returns a new LRUCache instance that partitions its keyspace
+// across shards independent LRUCache instances, each guarded by its own
+// lock, to reduce lock contention under concurrent access. capacity is the
+// total capacity across all shards; it is divided evenly (rounding up)
+// among them. hasher routes a key to a shard; if hasher is nil,
+// DefaultHasher is used. Panics if capacity <= 0 or shards <= 0.
+func NewSharded(capacity, shards int, hasher Hasher) LRUCache {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("invalid capacity: %d\n", capacity))
+	}
+	if shards <= 0 {
+		panic(fmt.Sprintf("invalid shards: %d\n", shards))
+	}
+	if hasher == nil {
+		hasher = DefaultHasher
+	}
+	shardCapacity := (capacity + shards - 1) / shards
+	sc := &shardedCache{
+		shards: make([]*lrucache, shards),
+		hasher: hasher,
+	}
+	for i := range sc.shards {
+		sc.shards[i] = newLRUCache(shardCapacity, 0, Callbacks{})
+	}
+	return sc
+}
+
+// DefaultHasher is the Hasher used by NewSharded when none is supplied. It
+// hashes strings, []byte, and integer key types directly, and falls back
+// to hashing the fmt.Sprint representation of the key for any other type.
+func DefaultHasher(key interface{}) uint64 {
+	h := fnv.New64a()
+	switch k := key.(type) {
+	case string:
+		h.Write([]byte(k))
+	case []byte:
+		h.Write(k)
+	case int:
+		writeUint64(h, uint64(k))
+	case int8:
+		writeUint64(h, uint64(k))
+	case int16:
+		writeUint64(h, uint64(k))
+	case int32:
+		writeUint64(h, uint64(k))
+	case int64:
+		writeUint64(h, uint64(k))
+	case uint:
+		writeUint64(h, uint64(k))
+	case uint8:
+		writeUint64(h, uint64(k))
+	case uint16:
+		writeUint64(h, uint64(k))
+	case uint32:
+		writeUint64(h, uint64(k))
+	case uint64:
+		writeUint64(h, k)
+	default:
+		h.Write([]byte(fmt.Sprint(k)))
+	}
+	return h.Sum64()
+}
+
+func writeUint64(h hash.Hash64, v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
+
+// shardedCache implements LRUCache by routing each operation to one of a
+// fixed set of independent lrucache shards by key hash.
+type shardedCache struct {
+	shards []*lrucache
+	hasher Hasher
+}
+
+func (sc *shardedCache) shardFor(key interface{}) *lrucache {
+	return sc.shards[sc.hasher(key)%uint64(len(sc.shards))]
+}
+
+func (sc *shardedCache) Get(key interface{}) (value interface{}) {
+	return sc.shardFor(key).Get(key)
+}
+
+func (sc *shardedCache) Put(key, value interface{}) {
+	sc.shardFor(key).Put(key, value)
+}
+
+func (sc *shardedCache) PutWithTTL(key, value interface{}, ttl time.Duration) {
+	sc.shardFor(key).PutWithTTL(key, value, ttl)
+}
+
+func (sc *shardedCache) PutE(key, value interface{}) error {
+	return sc.shardFor(key).PutE(key, value)
+}
+
+func (sc *shardedCache) PutIfAbsent(key, value interface{}) error {
+	return sc.shardFor(key).PutIfAbsent(key, value)
+}
+
+func (sc *shardedCache) Pin(key interface{}) {
+	sc.shardFor(key).Pin(key)
+}
+
+func (sc *shardedCache) Unpin(key interface{}) {
+	sc.shardFor(key).Unpin(key)
+}
+
+func (sc *shardedCache) Remove(key interface{}) (value interface{}) {
+	return sc.shardFor(key).Remove(key)
+}
+
+// Size returns the total number of entries currently in the cache, summed
+// across all shards.
+func (sc *shardedCache) Size() int {
+	total := 0
+	for _, s := range sc.shards {
+		total += s.Size()
+	}
+	return total
+}
+
+// Capacity returns the total capacity of the cache, summed across all
+// shards.
+func (sc *shardedCache) Capacity() int {
+	total := 0
+	for _, s := range sc.shards {
+		total += s.Capacity()
+	}
+	return total
+}
+
+func (sc *shardedCache) TTLSeconds() int64 {
+	if len(sc.shards) == 0 {
+		return 0
+	}
+	return sc.shards[0].TTLSeconds()
+}
+
+// Clear removes all entries from every shard.
+func (sc *shardedCache) Clear() {
+	for _, s := range sc.shards {
+		s.Clear()
+	}
+}
+
+// Stop terminates the sweeper goroutine, if any, for every shard.
+func (sc *shardedCache) Stop() {
+	for _, s := range sc.shards {
+		s.Stop()
+	}
+}
@@ -0,0 +1,38 @@
+package lrucache
+
+import "testing"
+
+func TestNewShardedRoutesAndRetrievesKeys(t *testing.T) {
+	c := NewSharded(4, 2, nil)
+	for i := 0; i < 4; i++ {
+		c.Put(i, i*10)
+	}
+	for i := 0; i < 4; i++ {
+		if v := c.Get(i); v != i*10 {
+			t.Fatalf("Get(%d) = %v, want %d", i, v, i*10)
+		}
+	}
+}
+
+func TestNewShardedCapacityDividesAcrossShards(t *testing.T) {
+	c := NewSharded(5, 2, nil)
+	if got := c.Capacity(); got != 6 {
+		t.Fatalf("Capacity() = %d, want 6 (3 per shard, rounded up, across 2 shards)", got)
+	}
+}
+
+func TestNewShardedUsesSuppliedHasher(t *testing.T) {
+	calls := 0
+	hasher := func(key interface{}) uint64 {
+		calls++
+		return 0
+	}
+	c := NewSharded(4, 4, hasher)
+	c.Put("a", 1)
+	if calls == 0 {
+		t.Fatalf("expected the supplied hasher to be called")
+	}
+	if v := c.Get("a"); v != 1 {
+		t.Fatalf("Get(a) = %v, want 1", v)
+	}
+}
@@ -0,0 +1,228 @@
+package lrucache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NewSIEVE returns a new LRUCache instance that uses the SIEVE eviction
+// policy instead of strict least-recently-used eviction. SIEVE keeps a
+// simple FIFO-ordered list of entries and a single "visited" bit per
+// entry, set whenever the entry is read, instead of relinking entries on
+// every access. This gives it higher hit ratios than LRU on scan-heavy and
+// web-like workloads while doing less work per Get. See
+// https://cachemon.github.io/SIEVE-website/ for background.
+//
+// The SIEVE cache does not support TTL-based expiration; TTLSeconds
+// always returns 0, PutWithTTL behaves like Put and ignores ttl, and Stop
+// is a no-op. Panics if capacity <= 0.
+func NewSIEVE(capacity int) LRUCache {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("invalid capacity: %d\n", capacity))
+	}
+	return &sievecache{
+		capacity: capacity,
+		hash:     make(map[interface{}]*sieveEntry),
+	}
+}
+
+type sieveEntry struct {
+	next, prev *sieveEntry
+	key        interface{}
+	value      interface{}
+	visited    bool
+	pinCount   int
+}
+
+type sievecache struct {
+	sync.Mutex
+	capacity int
+	head     *sieveEntry
+	tail     *sieveEntry
+	hand     *sieveEntry
+	hash     map[interface{}]*sieveEntry
+}
+
+// Get returns the value associated with the specified key and marks the
+// entry as visited. Unlike LRU, it does not move the entry within the
+// list. Returns nil if the key is not present in the cache.
+func (c *sievecache) Get(key interface{}) (value interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	e := c.hash[key]
+	if e == nil {
+		return nil
+	}
+	e.visited = true
+	return e.value
+}
+
+func (c *sievecache) Put(key, value interface{}) {
+	_ = c.PutE(key, value)
+}
+
+func (c *sievecache) PutE(key, value interface{}) error {
+	c.Lock()
+	defer c.Unlock()
+	if e := c.hash[key]; e != nil {
+		e.value = value
+		e.visited = true
+		return nil
+	}
+	if len(c.hash) >= c.capacity {
+		if !c.evict() {
+			return ErrCacheFull
+		}
+	}
+	e := &sieveEntry{key: key, value: value}
+	c.prepend(e)
+	c.hash[key] = e
+	return nil
+}
+
+// PutWithTTL behaves like Put; the SIEVE cache does not support expiration,
+// so ttl is ignored.
+func (c *sievecache) PutWithTTL(key, value interface{}, ttl time.Duration) {
+	c.Put(key, value)
+}
+
+func (c *sievecache) PutIfAbsent(key, value interface{}) error {
+	c.Lock()
+	defer c.Unlock()
+	if _, ok := c.hash[key]; ok {
+		return nil
+	}
+	if len(c.hash) >= c.capacity {
+		if !c.evict() {
+			return ErrCacheFull
+		}
+	}
+	e := &sieveEntry{key: key, value: value}
+	c.prepend(e)
+	c.hash[key] = e
+	return nil
+}
+
+func (c *sievecache) Pin(key interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	if e := c.hash[key]; e != nil {
+		e.pinCount++
+	}
+}
+
+func (c *sievecache) Unpin(key interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	if e := c.hash[key]; e != nil && e.pinCount > 0 {
+		e.pinCount--
+	}
+}
+
+func (c *sievecache) Remove(key interface{}) (value interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	e := c.hash[key]
+	if e == nil {
+		return nil
+	}
+	c.unlink(e)
+	delete(c.hash, key)
+	return e.value
+}
+
+func (c *sievecache) Size() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.hash)
+}
+
+func (c *sievecache) Capacity() int {
+	return c.capacity
+}
+
+// TTLSeconds always returns 0; the SIEVE cache does not support
+// TTL-based expiration.
+func (c *sievecache) TTLSeconds() int64 {
+	return 0
+}
+
+func (c *sievecache) Clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.head = nil
+	c.tail = nil
+	c.hand = nil
+	c.hash = make(map[interface{}]*sieveEntry)
+}
+
+// Stop is a no-op; the SIEVE cache has no background goroutine.
+func (c *sievecache) Stop() {}
+
+// evict removes one unpinned entry from the cache following the SIEVE
+// algorithm: starting from the hand (or the tail, if the hand is unset),
+// walk toward the head clearing visited entries back to false, wrapping
+// around to the tail if the head is reached, until an unvisited, unpinned
+// entry is found; pinned entries are passed over without altering their
+// visited bit. That entry is evicted and the hand is left at its
+// predecessor. A single pass only clears visited bits without evicting
+// when every unpinned entry was visited, so the walk is bounded to two
+// passes over the cache to give the second pass a chance to evict the now
+// cleared entries; evict returns false, without evicting anything, only
+// if every entry is pinned. Must be called under lock, and only when the
+// cache is non-empty.
+func (c *sievecache) evict() bool {
+	o := c.hand
+	if o == nil {
+		o = c.tail
+	}
+	for i := 0; i < 2*len(c.hash); i++ {
+		if o.pinCount == 0 {
+			if !o.visited {
+				c.hand = o.prev
+				c.unlink(o)
+				delete(c.hash, o.key)
+				return true
+			}
+			o.visited = false
+		}
+		if o.prev != nil {
+			o = o.prev
+		} else {
+			o = c.tail
+		}
+	}
+	return false
+}
+
+// must be called under lock
+func (c *sievecache) unlink(e *sieveEntry) {
+	if e.prev == nil {
+		c.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		c.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	if c.hand == e {
+		c.hand = e.prev
+	}
+	e.prev = nil
+	e.next = nil
+}
+
+// must be called under lock
+func (c *sievecache) prepend(e *sieveEntry) {
+	e.next = c.head
+	if c.head != nil {
+		c.head.prev = e
+	}
+	c.head = e
+	if c.tail == nil {
+		c.tail = e
+	}
+}
@@ -0,0 +1,43 @@
+package lrucache
+
+import "testing"
+
+func TestSIEVEEvictsWhenAllVisited(t *testing.T) {
+	c := NewSIEVE(2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Get("a")
+	c.Get("b")
+	if err := c.PutE("c", 3); err != nil {
+		t.Fatalf("PutE returned %v, want nil", err)
+	}
+	if got := c.Size(); got != 2 {
+		t.Fatalf("Size() = %d, want 2", got)
+	}
+	if v := c.Get("c"); v != 3 {
+		t.Fatalf("Get(%q) = %v, want 3", "c", v)
+	}
+}
+
+func TestSIEVEPreservesVisitedOverUnvisited(t *testing.T) {
+	c := NewSIEVE(2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Get("a") // mark a visited; b stays unvisited
+	c.Put("c", 3)
+	if c.Get("b") != nil {
+		t.Fatalf("expected b evicted (unvisited)")
+	}
+	if c.Get("a") == nil {
+		t.Fatalf("expected a retained (visited)")
+	}
+}
+
+func TestSIEVEAllPinnedReturnsErrCacheFull(t *testing.T) {
+	c := NewSIEVE(1)
+	c.Put("a", 1)
+	c.Pin("a")
+	if err := c.PutE("b", 2); err != ErrCacheFull {
+		t.Fatalf("PutE returned %v, want ErrCacheFull", err)
+	}
+}
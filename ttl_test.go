@@ -0,0 +1,54 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewWithTTLExpiresEntries(t *testing.T) {
+	c := NewWithTTL(10, 1)
+	defer c.Stop()
+	c.Put("a", 1)
+	if c.Get("a") == nil {
+		t.Fatalf("expected a present immediately after Put")
+	}
+	time.Sleep(1300 * time.Millisecond)
+	if c.Get("a") != nil {
+		t.Fatalf("expected a expired")
+	}
+}
+
+func TestPutWithTTLOverridesCacheTTL(t *testing.T) {
+	c := NewWithTTL(10, 3600)
+	defer c.Stop()
+	c.PutWithTTL("a", 1, time.Second)
+	time.Sleep(1300 * time.Millisecond)
+	if c.Get("a") != nil {
+		t.Fatalf("expected a expired despite the cache's longer default TTL")
+	}
+}
+
+func TestPutWithTTLZeroNeverExpires(t *testing.T) {
+	c := NewWithTTL(10, 1)
+	defer c.Stop()
+	c.PutWithTTL("a", 1, 0)
+	time.Sleep(1300 * time.Millisecond)
+	if c.Get("a") == nil {
+		t.Fatalf("expected a to survive since its entry TTL is disabled")
+	}
+}
+
+func TestStopPreventsSweeperStartingAfterward(t *testing.T) {
+	// New's TTL is disabled, so Stop() has no sweeper to stop yet; a later
+	// PutWithTTL must not lazily start one that Stop can never reach again.
+	c := New(10)
+	c.Stop()
+	c.PutWithTTL("a", 1, time.Hour)
+	lc := c.(*lrucache)
+	lc.Lock()
+	sweeping := lc.sweeping
+	lc.Unlock()
+	if sweeping {
+		t.Fatalf("expected no sweeper goroutine to start once the cache is stopped")
+	}
+}